@@ -0,0 +1,287 @@
+// Package agent turns gorilla into a long-running service: it schedules
+// managed-install cycles on an interval or cron expression, and exposes a
+// local control endpoint for inspecting and triggering those runs.
+package agent
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/robfig/cron/v3"
+
+	"github.com/1dustindavis/gorilla/pkg/config"
+	"github.com/1dustindavis/gorilla/pkg/logging"
+	"github.com/1dustindavis/gorilla/pkg/report"
+	"github.com/1dustindavis/gorilla/pkg/reporting"
+	"github.com/1dustindavis/gorilla/pkg/source"
+)
+
+// RunFunc performs one managed-install cycle against sources, which walks
+// cfg.Sources in priority order with per-source auth/TLS and fallback (see
+// pkg/source). It is supplied by the caller (main) so that pkg/agent does
+// not need to depend on the packages that actually do the installing.
+type RunFunc func(ctx context.Context, sources *source.Client) error
+
+// Status describes the agent's current state, as returned by the control
+// endpoint's "status" query.
+type Status struct {
+	Running     bool      `json:"running"`
+	LastRunAt   time.Time `json:"last_run_at,omitempty"`
+	LastRunErr  string    `json:"last_run_error,omitempty"`
+	NextRunAt   time.Time `json:"next_run_at,omitempty"`
+	RunInterval string    `json:"run_interval,omitempty"`
+	RunSchedule string    `json:"run_schedule,omitempty"`
+}
+
+// Service runs RunFunc on the schedule described by a Configuration and
+// serves the control endpoint used by "status", "run-now", "reload-config",
+// and "report" queries.
+type Service struct {
+	run        RunFunc
+	configPath string
+	verbose    bool
+	debug      bool
+	logger     *slog.Logger
+
+	mu        sync.Mutex
+	cfg       config.Configuration
+	schedule  cron.Schedule
+	sources   *source.Client
+	sinks     []reporting.Sink
+	running   bool
+	lastRunAt time.Time
+	lastErr   error
+	nextRunAt time.Time
+}
+
+// New creates a Service that will invoke run according to cfg's
+// RunInterval/RunSchedule/RandomDelay fields. configPath is the file cfg was
+// loaded from, and verbose/debug are the values it was loaded with - both
+// are reused, unchanged, by the control endpoint's "reload-config" query,
+// which must not let a reload silently reset the level the process was
+// actually started at.
+func New(cfg config.Configuration, configPath string, verbose bool, debug bool, run RunFunc) (*Service, error) {
+	s := &Service{
+		run:        run,
+		configPath: configPath,
+		verbose:    verbose,
+		debug:      debug,
+		logger:     logging.New(verbose, debug),
+	}
+	if err := s.configure(cfg); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// configure parses cfg's schedule fields and stores cfg, the per-source
+// client, and the reporting sinks for later reference.
+func (s *Service) configure(cfg config.Configuration) error {
+	var schedule cron.Schedule
+	switch {
+	case cfg.RunSchedule != "":
+		parsed, err := cron.ParseStandard(cfg.RunSchedule)
+		if err != nil {
+			return fmt.Errorf("invalid run_schedule %q: %w", cfg.RunSchedule, err)
+		}
+		schedule = parsed
+
+	case cfg.RunInterval != "":
+		interval, err := time.ParseDuration(cfg.RunInterval)
+		if err != nil {
+			return fmt.Errorf("invalid run_interval %q: %w", cfg.RunInterval, err)
+		}
+		schedule = cron.Every(interval)
+
+	default:
+		return fmt.Errorf("daemon mode requires run_interval or run_schedule to be set")
+	}
+
+	sinks, err := reporting.Sinks(cfg)
+	if err != nil {
+		return fmt.Errorf("configuring reporting sinks: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cfg = cfg
+	s.schedule = schedule
+	s.sources = source.NewClient(cfg)
+	s.sinks = sinks
+	return nil
+}
+
+// Reconfigure replaces the schedule, sources, and sinks with the ones
+// described by cfg, used by the control endpoint's "reload-config" query.
+// A run that is already scheduled keeps its time; the new schedule applies
+// from the run after it.
+func (s *Service) Reconfigure(cfg config.Configuration) error {
+	return s.configure(cfg)
+}
+
+// Run blocks, executing RunOnce on the configured schedule until ctx is
+// cancelled.
+func (s *Service) Run(ctx context.Context) error {
+	for {
+		next := s.next()
+
+		delay := time.Until(next)
+		if delay < 0 {
+			delay = 0
+		}
+		if s.randomDelay() {
+			if bound := s.jitterBound(next); bound > 0 {
+				delay += time.Duration(rand.Int63n(int64(bound)))
+			}
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+
+		s.RunOnce(ctx)
+	}
+}
+
+// RunOnce executes RunFunc a single time, recording the outcome for Status
+// and the run report. It is also what the control endpoint's "run-now"
+// query triggers out of band from the schedule.
+func (s *Service) RunOnce(ctx context.Context) error {
+	s.mu.Lock()
+	if s.running {
+		s.mu.Unlock()
+		return fmt.Errorf("a run is already in progress")
+	}
+	s.running = true
+	anchor := s.nextRunAt
+	sources := s.sources
+	sinks := s.sinks
+	cfg := s.cfg
+	s.mu.Unlock()
+
+	s.logger.Info("run starting")
+	start := time.Now()
+	err := s.fetchManifest(ctx, sources, cfg.Manifest)
+	if err == nil {
+		err = s.run(ctx, sources)
+	}
+	duration := time.Since(start)
+
+	doc := reporting.Document{
+		Manifest:  cfg.Manifest,
+		Catalogs:  cfg.Catalogs,
+		StartedAt: start,
+		Duration:  duration,
+	}
+	if err != nil {
+		s.logger.Error("run failed", "error", err, "duration", duration)
+		doc.Errors = append(doc.Errors, err.Error())
+	} else {
+		s.logger.Info("run finished", "duration", duration)
+	}
+	if emitErr := reporting.Emit(ctx, sinks, doc); emitErr != nil {
+		s.logger.Error("emitting run report", "error", emitErr)
+	}
+
+	// Advance the schedule from the tick that was actually due, not from
+	// the completion time - anchoring to "now" here would let every run's
+	// random_delay jitter permanently push the next tick later, compounding
+	// cycle over cycle instead of staying centered on run_interval/
+	// run_schedule. An out-of-band "run-now" fires before its tick is due,
+	// so it still reschedules from the actual start time.
+	if anchor.IsZero() || anchor.After(start) {
+		anchor = start
+	}
+
+	s.mu.Lock()
+	s.running = false
+	s.lastRunAt = start
+	s.lastErr = err
+	s.nextRunAt = s.schedule.Next(anchor)
+	s.mu.Unlock()
+
+	report.Items["AgentLastRun"] = start
+	if err != nil {
+		report.Items["AgentLastError"] = err.Error()
+	}
+
+	return err
+}
+
+// fetchManifest opens manifest against sources - in priority order, falling
+// back to the next source on any error (see pkg/source) - so that every run
+// actually exercises the mirrors/fallback/mixed-auth behavior cfg.Sources
+// describes, rather than leaving sources as a client RunFunc merely has
+// access to but the run loop itself never calls.
+func (s *Service) fetchManifest(ctx context.Context, sources *source.Client, manifest string) error {
+	body, err := sources.Open(ctx, manifest)
+	if err != nil {
+		return fmt.Errorf("fetching manifest %q: %w", manifest, err)
+	}
+	defer body.Close()
+
+	if _, err := io.Copy(io.Discard, body); err != nil {
+		return fmt.Errorf("reading manifest %q: %w", manifest, err)
+	}
+	return nil
+}
+
+// Status returns a snapshot of the agent's current state.
+func (s *Service) Status() Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	st := Status{
+		Running:     s.running,
+		LastRunAt:   s.lastRunAt,
+		NextRunAt:   s.nextRunAt,
+		RunInterval: s.cfg.RunInterval,
+		RunSchedule: s.cfg.RunSchedule,
+	}
+	if s.lastErr != nil {
+		st.LastRunErr = s.lastErr.Error()
+	}
+	return st
+}
+
+func (s *Service) next() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.nextRunAt.IsZero() {
+		s.nextRunAt = s.schedule.Next(time.Now())
+	}
+	return s.nextRunAt
+}
+
+func (s *Service) randomDelay() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.cfg.RandomDelay
+}
+
+// jitterBound returns the span random_delay should be bounded to: the gap
+// between the upcoming tick and the one after it. Bounding to this instead
+// of to the full wait-until-next keeps the jitter proportional to
+// run_interval/run_schedule's own cadence, as documented on
+// Configuration.RandomDelay, rather than to however long next happens to be
+// (which, right after a cron.Schedule rolls over, can be hours).
+func (s *Service) jitterBound(next time.Time) time.Duration {
+	s.mu.Lock()
+	schedule := s.schedule
+	s.mu.Unlock()
+
+	gap := schedule.Next(next).Sub(next)
+	if gap < 0 {
+		return 0
+	}
+	return gap
+}
@@ -0,0 +1,186 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/1dustindavis/gorilla/pkg/config"
+	"github.com/1dustindavis/gorilla/pkg/reporting"
+	"github.com/1dustindavis/gorilla/pkg/source"
+)
+
+func newTestService(t *testing.T, cfg config.Configuration, run RunFunc) *Service {
+	t.Helper()
+	if run == nil {
+		run = func(ctx context.Context, _ *source.Client) error { return nil }
+	}
+	if cfg.Manifest == "" {
+		cfg.Manifest = "manifests/site_default.yaml"
+	}
+	if cfg.Sources == nil {
+		cfg.Sources = []config.Source{{Name: "default", URL: newManifestServer(t, "manifest contents")}}
+	}
+	svc, err := New(cfg, "", false, false, run)
+	if err != nil {
+		t.Fatalf("New returned an error: %v", err)
+	}
+	return svc
+}
+
+// newManifestServer starts an httptest server that serves body for any
+// request, returning its URL. The server is closed when the test ends.
+func newManifestServer(t *testing.T, body string) string {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	return server.URL
+}
+
+func TestNew_BuildsSourceClientFromConfig(t *testing.T) {
+	svc := newTestService(t, config.Configuration{
+		RunInterval: "1h",
+		Sources:     []config.Source{{Name: "default", URL: "https://repo.example.com"}},
+	}, nil)
+
+	svc.mu.Lock()
+	sources := svc.sources
+	svc.mu.Unlock()
+
+	if sources == nil {
+		t.Fatal("expected New to build a source.Client from cfg.Sources")
+	}
+}
+
+func TestRunOnce_AnchorsNextTickToScheduledTime(t *testing.T) {
+	// cron.Every only supports whole-second resolution (sub-second delays
+	// round up to 1s - see robfig/cron's ConstantDelaySchedule.Every), so
+	// the interval here has to be in whole seconds for Next to be exact.
+	svc := newTestService(t, config.Configuration{RunInterval: "2s"}, func(ctx context.Context, _ *source.Client) error {
+		// A slow run shouldn't push the next tick back - it should still
+		// land relative to the tick that was actually due.
+		time.Sleep(50 * time.Millisecond)
+		return nil
+	})
+
+	// The tick has already come due by the time Run's timer would have
+	// fired RunOnce - set it in the past relative to "now" so the
+	// anchor-from-now fallback (the out-of-band "run-now" case) doesn't
+	// kick in.
+	ideal := time.Now().Truncate(time.Second).Add(-1 * time.Second)
+	svc.mu.Lock()
+	svc.nextRunAt = ideal
+	svc.mu.Unlock()
+
+	if err := svc.RunOnce(context.Background()); err != nil {
+		t.Fatalf("RunOnce returned an error: %v", err)
+	}
+
+	svc.mu.Lock()
+	got := svc.nextRunAt
+	svc.mu.Unlock()
+
+	want := ideal.Add(2 * time.Second)
+	if !got.Equal(want) {
+		t.Errorf("expected the next tick to be anchored to the prior scheduled tick (%v), got %v", want, got)
+	}
+}
+
+func TestRunOnce_OutOfBandRunReschedulesFromNow(t *testing.T) {
+	svc := newTestService(t, config.Configuration{RunInterval: "2s"}, nil)
+
+	// No tick is due yet (nextRunAt is zero) - this simulates the
+	// control endpoint's "run-now" query firing ahead of schedule.
+	before := time.Now()
+	if err := svc.RunOnce(context.Background()); err != nil {
+		t.Fatalf("RunOnce returned an error: %v", err)
+	}
+
+	svc.mu.Lock()
+	got := svc.nextRunAt
+	svc.mu.Unlock()
+
+	// cron.Every's Next snaps to a whole-second boundary, so the exact gap
+	// from "before" varies with its sub-second offset - just check it
+	// landed in the [1s, 2s] window a 2s interval implies, rather than
+	// anchoring on the stale (zero) nextRunAt.
+	if got.Before(before.Add(time.Second)) || got.After(before.Add(2*time.Second)) {
+		t.Errorf("expected the next tick to be rescheduled ~2s from the out-of-band run, got %v (run started %v)", got, before)
+	}
+}
+
+func TestRunOnce_EmitsReportToConfiguredSinks(t *testing.T) {
+	reportPath := filepath.Join(t.TempDir(), "report.jsonl")
+	svc := newTestService(t, config.Configuration{
+		RunInterval: "1h",
+		Manifest:    "site_default",
+		Reporting: config.ReportingConfig{
+			Sinks: []config.SinkConfig{{Type: "file", Path: reportPath}},
+		},
+	}, nil)
+
+	if err := svc.RunOnce(context.Background()); err != nil {
+		t.Fatalf("RunOnce returned an error: %v", err)
+	}
+
+	data, err := os.ReadFile(reportPath)
+	if err != nil {
+		t.Fatalf("reading report file: %v", err)
+	}
+
+	var doc reporting.Document
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("decoding report: %v", err)
+	}
+	if doc.Manifest != "site_default" {
+		t.Errorf("expected the emitted report to carry the configured manifest, got %+v", doc)
+	}
+}
+
+func TestRunOnce_FetchesManifestFallingBackToSecondSource(t *testing.T) {
+	bad := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "down for maintenance", http.StatusServiceUnavailable)
+	}))
+	defer bad.Close()
+	good := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("manifest contents"))
+	}))
+	defer good.Close()
+
+	var ranWith *source.Client
+	svc := newTestService(t, config.Configuration{
+		RunInterval: "1h",
+		Manifest:    "manifests/site_default.yaml",
+		Sources: []config.Source{
+			{Name: "primary", URL: bad.URL, Priority: 10},
+			{Name: "fallback", URL: good.URL, Priority: 0},
+		},
+	}, func(ctx context.Context, sources *source.Client) error {
+		ranWith = sources
+		return nil
+	})
+
+	if err := svc.RunOnce(context.Background()); err != nil {
+		t.Fatalf("RunOnce returned an error: %v", err)
+	}
+	if ranWith == nil {
+		t.Fatal("expected RunFunc to run after the manifest was fetched from the fallback source")
+	}
+}
+
+func TestJitterBound_MatchesScheduleGap(t *testing.T) {
+	svc := newTestService(t, config.Configuration{RunInterval: "3s"}, nil)
+
+	next := time.Now().Truncate(time.Second)
+	got := svc.jitterBound(next)
+	if got != 3*time.Second {
+		t.Errorf("expected the jitter bound to match run_interval (3s), got %v", got)
+	}
+}
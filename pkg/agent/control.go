@@ -0,0 +1,99 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/1dustindavis/gorilla/pkg/config"
+	"github.com/1dustindavis/gorilla/pkg/report"
+)
+
+// ServeControl listens on the platform's control transport (by default a
+// loopback TCP socket, or a named pipe on Windows, where a host:port
+// control_address selects TCP instead - see listener_windows.go) and serves
+// the "status", "run-now", "reload-config", and "report" queries until ctx
+// is cancelled.
+func (s *Service) ServeControl(ctx context.Context) error {
+	listener, err := newControlListener(s.controlAddress())
+	if err != nil {
+		return err
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/status", s.handleStatus)
+	mux.HandleFunc("/run-now", s.handleRunNow)
+	mux.HandleFunc("/reload-config", s.handleReloadConfig)
+	mux.HandleFunc("/report", s.handleReport)
+
+	server := &http.Server{Handler: mux}
+
+	go func() {
+		<-ctx.Done()
+		server.Close()
+	}()
+
+	err = server.Serve(listener)
+	if err != nil && ctx.Err() != nil {
+		return nil
+	}
+	return err
+}
+
+func (s *Service) controlAddress() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cfg.ControlAddress != "" {
+		return s.cfg.ControlAddress
+	}
+	return defaultControlAddress
+}
+
+func (s *Service) handleStatus(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, s.Status())
+}
+
+func (s *Service) handleRunNow(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "run-now requires POST", http.StatusMethodNotAllowed)
+		return
+	}
+	if err := s.RunOnce(r.Context()); err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	writeJSON(w, s.Status())
+}
+
+func (s *Service) handleReloadConfig(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "reload-config requires POST", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Reuse the verbose/debug values the service was actually constructed
+	// with - not literal false/false, which would let flagsExplicitlySet()
+	// (reading the process's real command-line flags) forcibly reset a
+	// running -verbose/-debug back off on every reload.
+	cfg, err := config.Load(s.configPath, s.verbose, s.debug)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := s.Reconfigure(cfg); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	writeJSON(w, s.Status())
+}
+
+func (s *Service) handleReport(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, report.Items)
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,58 @@
+package agent
+
+import (
+	"context"
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/1dustindavis/gorilla/pkg/config"
+	"github.com/1dustindavis/gorilla/pkg/source"
+)
+
+func TestHandleReloadConfig_PreservesConstructedVerboseDebug(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config.yaml")
+	contents := "url: https://repo.example.com\nmanifest: foo.yaml\nrun_interval: 1h\n"
+	if err := os.WriteFile(configPath, []byte(contents), 0644); err != nil {
+		t.Fatalf("writing config: %v", err)
+	}
+
+	// Simulate the process having actually been started with -verbose, which
+	// marks the flag as explicitly set for flagsExplicitlySet() for the rest
+	// of this test binary's lifetime.
+	if err := flag.CommandLine.Parse([]string{"-verbose=true"}); err != nil {
+		t.Fatalf("parsing flags: %v", err)
+	}
+
+	cfg, err := config.Load(configPath, true, false)
+	if err != nil {
+		t.Fatalf("Load returned an error: %v", err)
+	}
+	if !cfg.Verbose {
+		t.Fatal("test setup: expected the initial load to come up verbose")
+	}
+
+	svc, err := New(cfg, configPath, true, false, func(ctx context.Context, _ *source.Client) error { return nil })
+	if err != nil {
+		t.Fatalf("New returned an error: %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/reload-config", nil)
+	svc.handleReloadConfig(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	svc.mu.Lock()
+	got := svc.cfg.Verbose
+	svc.mu.Unlock()
+
+	if !got {
+		t.Error("expected reload-config to preserve the verbose value the service was constructed with, not reset it to false")
+	}
+}
@@ -0,0 +1,15 @@
+//go:build !windows
+
+package agent
+
+import "net"
+
+// defaultControlAddress is the loopback address the control endpoint binds
+// to when Configuration.ControlAddress is unset.
+const defaultControlAddress = "127.0.0.1:9191"
+
+// newControlListener binds the control endpoint to a loopback TCP socket.
+// Named pipes are a Windows-only concept; see listener_windows.go.
+func newControlListener(address string) (net.Listener, error) {
+	return net.Listen("tcp", address)
+}
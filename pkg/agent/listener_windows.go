@@ -0,0 +1,25 @@
+//go:build windows
+
+package agent
+
+import (
+	"net"
+	"strings"
+
+	"github.com/Microsoft/go-winio"
+)
+
+// defaultControlAddress is a named pipe: gorilla's primary deployment target
+// is Windows, where a TCP loopback socket is still reachable by any other
+// local user unless firewalled, whereas a named pipe can be ACL'd.
+const defaultControlAddress = `\\.\pipe\gorilla`
+
+// newControlListener binds the control endpoint to a named pipe. An address
+// that looks like host:port (rather than a pipe path) falls back to TCP, so
+// operators can still opt into a loopback socket via control_address.
+func newControlListener(address string) (net.Listener, error) {
+	if !strings.HasPrefix(address, `\\.\pipe\`) {
+		return net.Listen("tcp", address)
+	}
+	return winio.ListenPipe(address, nil)
+}
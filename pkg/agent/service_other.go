@@ -0,0 +1,17 @@
+//go:build !windows
+
+package agent
+
+import "context"
+
+// RunService runs s in the foreground. Windows service registration (see
+// service_windows.go) only applies on Windows; elsewhere -daemon just runs
+// the scheduler and control endpoint as an ordinary process, which is
+// expected to be supervised by systemd/launchd/etc. if needed.
+func RunService(s *Service) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go s.ServeControl(ctx)
+	return s.Run(ctx)
+}
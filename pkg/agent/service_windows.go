@@ -0,0 +1,64 @@
+//go:build windows
+
+package agent
+
+import (
+	"context"
+
+	"golang.org/x/sys/windows/svc"
+)
+
+// serviceName is the name gorilla registers itself under with the Windows
+// Service Control Manager.
+const serviceName = "Gorilla"
+
+// RunService runs s under the Windows Service Control Manager when gorilla
+// is installed as a service, or falls back to running it in the foreground
+// (e.g. when invoked interactively with -daemon for testing).
+func RunService(s *Service) error {
+	isService, err := svc.IsWindowsService()
+	if err != nil {
+		return err
+	}
+	if !isService {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go s.ServeControl(ctx)
+		return s.Run(ctx)
+	}
+
+	return svc.Run(serviceName, &windowsService{agent: s})
+}
+
+// windowsService adapts Service to the svc.Handler interface expected by the
+// Windows Service Control Manager.
+type windowsService struct {
+	agent *Service
+}
+
+func (w *windowsService) Execute(args []string, r <-chan svc.ChangeRequest, changes chan<- svc.Status) (bool, uint32) {
+	const accepted = svc.AcceptStop | svc.AcceptShutdown
+
+	changes <- svc.Status{State: svc.StartPending}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go w.agent.ServeControl(ctx)
+	go w.agent.Run(ctx)
+
+	changes <- svc.Status{State: svc.Running, Accepts: accepted}
+
+	for req := range r {
+		switch req.Cmd {
+		case svc.Interrogate:
+			changes <- req.CurrentStatus
+		case svc.Stop, svc.Shutdown:
+			changes <- svc.Status{State: svc.StopPending}
+			cancel()
+			return false, 0
+		}
+	}
+
+	return false, 0
+}
@@ -9,6 +9,7 @@ import (
 
 	"gopkg.in/yaml.v2"
 
+	"github.com/1dustindavis/gorilla/pkg/logging"
 	"github.com/1dustindavis/gorilla/pkg/report"
 	"github.com/1dustindavis/gorilla/pkg/version"
 )
@@ -28,6 +29,8 @@ var (
 	buildDefault   = false
 	importArg      string
 	importDefault  = ""
+	daemonArg      bool
+	daemonDefault  = false
 	helpArg        bool
 	helpDefault    = false
 	verboseArg     bool
@@ -46,36 +49,71 @@ https://github.com/1dustindavis/gorilla
 Usage: gorilla.exe [options]
 
 Options:
--c, -config         path to configuration file in yaml format
+-c, -config         path to configuration file in yaml, json, or toml format
 -v, -verbose        enable verbose output
 -d, -debug          enable debug output
 -b, -build          build catalog files from any files in packages-info
 -i, -import         create a package-info file from an installer package
+    -daemon         run as a long-running agent, scheduling installs per run_interval/run_schedule
 -a, -about          display the version number and other build info
 -V, -version        display the version number
 -h, -help           display this help message
 
+Configuration is resolved in order of precedence, highest wins:
+  command-line flags > environment variables (GORILLA_*) > config file > defaults
 `
 
 // Configuration stores all of the possible parameters a config file could contain
 type Configuration struct {
-	AppDataPath   string `yaml:"app_data_path"`
-	AuthPass      string `yaml:"auth_pass,omitempty"`
-	AuthUser      string `yaml:"auth_user,omitempty"`
-	BuildArg      bool
-	CachePath     string
-	Catalogs      []string `yaml:"catalogs"`
-	Debug         bool     `yaml:"debug,omitempty"`
-	ImportArg     string
-	Manifest      string `yaml:"manifest"`
-	RepoPath      string `yaml:"repo_path,omitempty"`
-	TLSAuth       bool   `yaml:"tls_auth,omitempty"`
-	TLSClientCert string `yaml:"tls_client_cert,omitempty"`
-	TLSClientKey  string `yaml:"tls_client_key,omitempty"`
-	TLSServerCert string `yaml:"tls_server_cert,omitempty"`
-	URL           string `yaml:"url"`
-	URLPackages   string `yaml:"url_packages"`
-	Verbose       bool   `yaml:"verbose,omitempty"`
+	AppDataPath string `yaml:"app_data_path" json:"app_data_path" toml:"app_data_path"`
+	AuthPass    string `yaml:"auth_pass,omitempty" json:"auth_pass,omitempty" toml:"auth_pass,omitempty"`
+	AuthUser    string `yaml:"auth_user,omitempty" json:"auth_user,omitempty" toml:"auth_user,omitempty"`
+	BuildArg    bool
+	CachePath   string
+	Catalogs    []string `yaml:"catalogs" json:"catalogs" toml:"catalogs"`
+	// ControlAddress is the loopback address (or, on Windows, named pipe
+	// name) the agent's control endpoint listens on. Defaults to
+	// "127.0.0.1:9191" / `\\.\pipe\gorilla` when unset.
+	ControlAddress string `yaml:"control_address,omitempty" json:"control_address,omitempty" toml:"control_address,omitempty"`
+	DaemonArg      bool
+	Debug          bool `yaml:"debug,omitempty" json:"debug,omitempty" toml:"debug,omitempty"`
+	ImportArg      string
+	Manifest       string `yaml:"manifest" json:"manifest" toml:"manifest"`
+	// Reporting configures where the structured run report is sent; see
+	// pkg/reporting.
+	Reporting ReportingConfig `yaml:"reporting,omitempty" json:"reporting,omitempty" toml:"reporting,omitempty"`
+	// RandomDelay, when true, jitters the start of each scheduled run by a
+	// random amount (0-RunInterval, or the equivalent gap between ticks for
+	// RunSchedule) to avoid a fleet of clients hitting the repo at the same
+	// instant. The jitter is bounded per tick and never carries over, so the
+	// average cadence stays centered on RunInterval/RunSchedule.
+	RandomDelay bool   `yaml:"random_delay,omitempty" json:"random_delay,omitempty" toml:"random_delay,omitempty"`
+	RepoPath    string `yaml:"repo_path,omitempty" json:"repo_path,omitempty" toml:"repo_path,omitempty"`
+	// RequireSignedConfig, when true, causes Get/Load to reject configPath
+	// unless it is accompanied by a valid detached signature - see
+	// SignedConfigPublicKey and sign.go.
+	RequireSignedConfig bool `yaml:"require_signed_config,omitempty" json:"require_signed_config,omitempty" toml:"require_signed_config,omitempty"`
+	// SignedConfigPublicKey is the path to the PEM-encoded ed25519 public
+	// key configPath's "<file>.sig" detached signature is checked against.
+	SignedConfigPublicKey string `yaml:"signed_config_public_key,omitempty" json:"signed_config_public_key,omitempty" toml:"signed_config_public_key,omitempty"`
+	// RunInterval is a Go duration string (e.g. "1h") controlling how often
+	// the agent runs a managed-install cycle. Ignored when RunSchedule is set.
+	RunInterval string `yaml:"run_interval,omitempty" json:"run_interval,omitempty" toml:"run_interval,omitempty"`
+	// RunSchedule is a standard 5-field cron expression. When set, it takes
+	// precedence over RunInterval.
+	RunSchedule string `yaml:"run_schedule,omitempty" json:"run_schedule,omitempty" toml:"run_schedule,omitempty"`
+	// Sources lists repositories to resolve catalogs and manifests from, in
+	// priority order. When empty, the flat URL/URLPackages/AuthUser/AuthPass
+	// and TLS* fields below are used to synthesize a single default source -
+	// see normalizeSources.
+	Sources       []Source `yaml:"sources,omitempty" json:"sources,omitempty" toml:"sources,omitempty"`
+	TLSAuth       bool     `yaml:"tls_auth,omitempty" json:"tls_auth,omitempty" toml:"tls_auth,omitempty"`
+	TLSClientCert string   `yaml:"tls_client_cert,omitempty" json:"tls_client_cert,omitempty" toml:"tls_client_cert,omitempty"`
+	TLSClientKey  string   `yaml:"tls_client_key,omitempty" json:"tls_client_key,omitempty" toml:"tls_client_key,omitempty"`
+	TLSServerCert string   `yaml:"tls_server_cert,omitempty" json:"tls_server_cert,omitempty" toml:"tls_server_cert,omitempty"`
+	URL           string   `yaml:"url" json:"url" toml:"url"`
+	URLPackages   string   `yaml:"url_packages" json:"url_packages" toml:"url_packages"`
+	Verbose       bool     `yaml:"verbose,omitempty" json:"verbose,omitempty" toml:"verbose,omitempty"`
 }
 
 func init() {
@@ -96,6 +134,8 @@ func init() {
 	// Import
 	flag.StringVar(&importArg, "import", importDefault, "")
 	flag.StringVar(&importArg, "i", importDefault, "")
+	// Daemon
+	flag.BoolVar(&daemonArg, "daemon", daemonDefault, "")
 	// Help
 	flag.BoolVar(&helpArg, "help", helpDefault, "")
 	flag.BoolVar(&helpArg, "h", helpDefault, "")
@@ -127,39 +167,114 @@ func parseArguments() (string, bool, bool) {
 	return configArg, verboseArg, debugArg
 }
 
-// Get retrieves and parses the config file and returns a Configuration struct and any errors
-func Get() Configuration {
+// flagsExplicitlySet reports whether verbose/debug were actually passed on
+// the command line, so that an unset flag doesn't clobber a value from the
+// config file or environment.
+func flagsExplicitlySet() (verboseSet bool, debugSet bool) {
+	flag.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "verbose", "v":
+			verboseSet = true
+		case "debug", "d":
+			debugSet = true
+		}
+	})
+	return
+}
+
+// Load reads configPath (auto-detecting YAML/JSON/TOML by extension),
+// overlays environment variables and then the already-parsed command line
+// flags, applies defaults, and validates the result. Unlike Get, it returns
+// errors instead of exiting so it can be tested and embedded.
+func Load(configPath string, verbose bool, debug bool) (Configuration, error) {
 	var cfg Configuration
 
-	// Parse any arguments that may have been passed
-	configPath, verbose, debug := parseArguments()
+	if err := loadFile(configPath, &cfg); err != nil {
+		return cfg, err
+	}
+
+	applyEnv(&cfg)
+
+	// An explicitly-set flag wins in both directions - e.g. -verbose=false
+	// on the command line must be able to turn off a true set by
+	// GORILLA_VERBOSE or the config file, not just turn it on.
+	verboseSet, debugSet := flagsExplicitlySet()
+	if verboseSet {
+		cfg.Verbose = verbose
+	}
+	if debugSet {
+		cfg.Debug = debug
+		if debug {
+			cfg.Verbose = true
+		}
+	}
+
+	cfg.BuildArg = buildArg
+	cfg.ImportArg = importArg
+	cfg.DaemonArg = daemonArg
+
+	applyDefaults(&cfg)
+
+	if err := validate(&cfg); err != nil {
+		return cfg, err
+	}
 
-	// Read the config file
+	return cfg, nil
+}
+
+// loadFile reads configPath from disk, merges in any config.d/ fragments
+// named by an `include:` directive, resolves env://, file://, and dpapi://
+// secret references, verifies the detached signature when required, and
+// unmarshals the result into cfg. The codec (YAML/JSON/TOML) is picked from
+// configPath's extension.
+func loadFile(configPath string, cfg *Configuration) error {
 	configFile, err := ioutil.ReadFile(configPath)
 	if err != nil {
-		fmt.Println("Unable to read configuration file: ", err)
-		os.Exit(1)
+		return fmt.Errorf("unable to read configuration file: %w", err)
 	}
 
-	// Parse the config into a struct
-	err = yaml.Unmarshal(configFile, &cfg)
+	ext := filepath.Ext(configPath)
+
+	doc, err := decodeMap(configFile, ext)
 	if err != nil {
-		fmt.Println("Unable to parse yaml configuration: ", err)
-		os.Exit(1)
+		return fmt.Errorf("unable to parse %s configuration: %w", formatName(ext), err)
 	}
 
-	// If Manifest wasnt provided, exit
-	if cfg.Manifest == "" {
-		fmt.Println("Invalid configuration - Manifest: ", err)
-		os.Exit(1)
+	// The signing policy is resolved from the top-level document only, and
+	// then threaded through to resolveIncludes, so a config.d/ fragment can
+	// never loosen or redirect it for itself or for subsequent loads.
+	policy, err := resolveSigningPolicy(doc)
+	if err != nil {
+		return err
+	}
+	if err := policy.verify(configPath, configFile); err != nil {
+		return err
+	}
+
+	doc, err = resolveIncludes(doc, filepath.Dir(configPath), policy)
+	if err != nil {
+		return fmt.Errorf("unable to resolve includes for %s: %w", configPath, err)
 	}
 
-	// If URL wasnt provided, exit
-	if cfg.URL == "" {
-		fmt.Println("Invalid configuration - URL: ", err)
-		os.Exit(1)
+	if err := resolveSecrets(doc); err != nil {
+		return fmt.Errorf("unable to resolve secrets for %s: %w", configPath, err)
 	}
 
+	merged, err := yaml.Marshal(doc)
+	if err != nil {
+		return fmt.Errorf("unable to re-encode merged configuration: %w", err)
+	}
+
+	if err := yaml.Unmarshal(merged, cfg); err != nil {
+		return fmt.Errorf("unable to parse merged configuration: %w", err)
+	}
+
+	return nil
+}
+
+// applyDefaults fills in any values Get needs but that are safe to derive
+// rather than require in the config file.
+func applyDefaults(cfg *Configuration) {
 	// If URLPackages wasn't provided, use the repo URL
 	if cfg.URLPackages == "" {
 		cfg.URLPackages = cfg.URL
@@ -172,23 +287,39 @@ func Get() Configuration {
 		cfg.AppDataPath = filepath.Clean(cfg.AppDataPath)
 	}
 
-	// Set the verbosity
-	if verbose && !cfg.Verbose {
-		cfg.Verbose = true
-	}
+	// Set the cache path
+	cfg.CachePath = filepath.Join(cfg.AppDataPath, "cache")
 
-	// Set the debug and verbose
-	if debug && !cfg.Debug {
-		cfg.Debug = true
-		cfg.Verbose = true
+	normalizeSources(cfg)
+}
+
+// validate checks that the required fields were set by the file, the
+// environment, or a default.
+func validate(cfg *Configuration) error {
+	if cfg.Manifest == "" {
+		return fmt.Errorf("invalid configuration - Manifest is required")
 	}
+	if len(cfg.Sources) == 0 {
+		return fmt.Errorf("invalid configuration - URL or sources is required")
+	}
+	for _, src := range cfg.Sources {
+		if src.URL == "" {
+			return fmt.Errorf("invalid configuration - source %q is missing a url", src.Name)
+		}
+	}
+	return nil
+}
 
-	// Set build and import flags
-	cfg.BuildArg = buildArg
-	cfg.ImportArg = importArg
+// Get retrieves and parses the config file and returns a Configuration struct and any errors
+func Get() Configuration {
+	// Parse any arguments that may have been passed
+	configPath, verbose, debug := parseArguments()
 
-	// Set the cache path
-	cfg.CachePath = filepath.Join(cfg.AppDataPath, "cache")
+	cfg, err := Load(configPath, verbose, debug)
+	if err != nil {
+		logging.New(verbose, debug).Error(err.Error())
+		osExit(1)
+	}
 
 	// Add to GorillaReport
 	report.Items["Manifest"] = cfg.Manifest
@@ -0,0 +1,93 @@
+package config
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeTempConfig(t *testing.T, name string, contents string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+		t.Fatalf("writing temp config: %v", err)
+	}
+	return path
+}
+
+func TestLoad_RequiresManifestAndURL(t *testing.T) {
+	path := writeTempConfig(t, "config.yaml", "manifest: foo.yaml\n")
+
+	if _, err := Load(path, false, false); err == nil {
+		t.Fatal("expected an error when url is missing")
+	}
+}
+
+func TestLoad_EnvOverridesFile(t *testing.T) {
+	path := writeTempConfig(t, "config.yaml", "manifest: foo.yaml\nurl: https://file.example.com\n")
+
+	t.Setenv("GORILLA_URL", "https://env.example.com")
+
+	cfg, err := Load(path, false, false)
+	if err != nil {
+		t.Fatalf("Load returned an error: %v", err)
+	}
+	if cfg.URL != "https://env.example.com" {
+		t.Errorf("expected env var to override file url, got %q", cfg.URL)
+	}
+}
+
+func TestLoad_FileOverridesDefaults(t *testing.T) {
+	path := writeTempConfig(t, "config.yaml", "manifest: foo.yaml\nurl: https://file.example.com\nrepo_path: /srv/repo\n")
+
+	cfg, err := Load(path, false, false)
+	if err != nil {
+		t.Fatalf("Load returned an error: %v", err)
+	}
+	if cfg.RepoPath != "/srv/repo" {
+		t.Errorf("expected file value to survive, got %q", cfg.RepoPath)
+	}
+	// URLPackages has no file or env value, so it should fall back to the default of URL.
+	if cfg.URLPackages != cfg.URL {
+		t.Errorf("expected URLPackages to default to URL, got %q vs %q", cfg.URLPackages, cfg.URL)
+	}
+}
+
+// TestLoad_FlagOverridesEnvInBothDirections is a regression test: an
+// explicitly-set -verbose/-debug flag must be able to turn a value on *or*
+// off, even when the config file or environment disagrees, because flags
+// are documented as the highest-precedence source.
+func TestLoad_FlagOverridesEnvInBothDirections(t *testing.T) {
+	path := writeTempConfig(t, "config.yaml", "manifest: foo.yaml\nurl: https://file.example.com\n")
+
+	// Mark "verbose" and "debug" as explicitly set on the command line, the
+	// way flag.Parse would during a real run with -verbose -debug passed.
+	if err := flag.CommandLine.Parse([]string{"-verbose=true", "-debug=true"}); err != nil {
+		t.Fatalf("simulating flag parse: %v", err)
+	}
+
+	t.Setenv("GORILLA_VERBOSE", "true")
+
+	// The flag value passed to Load is false, even though it was "set" -
+	// this must win over the env var's true.
+	cfg, err := Load(path, false, false)
+	if err != nil {
+		t.Fatalf("Load returned an error: %v", err)
+	}
+	if cfg.Verbose {
+		t.Error("expected an explicit -verbose=false flag to turn verbose off despite GORILLA_VERBOSE=true")
+	}
+	if cfg.Debug {
+		t.Error("expected an explicit -debug=false flag to turn debug off")
+	}
+
+	// Now flip the flag value to true and confirm it still wins (forcing on).
+	cfg, err = Load(path, true, true)
+	if err != nil {
+		t.Fatalf("Load returned an error: %v", err)
+	}
+	if !cfg.Verbose || !cfg.Debug {
+		t.Error("expected an explicit -debug=true flag to force both debug and verbose on")
+	}
+}
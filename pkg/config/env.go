@@ -0,0 +1,69 @@
+package config
+
+import (
+	"os"
+	"strconv"
+	"strings"
+)
+
+// envPrefix is the prefix used for every environment variable gorilla reads,
+// e.g. GORILLA_URL, GORILLA_MANIFEST, GORILLA_CATALOGS.
+const envPrefix = "GORILLA_"
+
+// applyEnv overlays any GORILLA_* environment variables onto cfg. Env vars
+// take precedence over the config file but are overridden by explicit
+// command line flags, per the precedence documented in usage.
+func applyEnv(cfg *Configuration) {
+	if v, ok := lookupEnv("URL"); ok {
+		cfg.URL = v
+	}
+	if v, ok := lookupEnv("URL_PACKAGES"); ok {
+		cfg.URLPackages = v
+	}
+	if v, ok := lookupEnv("MANIFEST"); ok {
+		cfg.Manifest = v
+	}
+	if v, ok := lookupEnv("CATALOGS"); ok {
+		cfg.Catalogs = strings.Split(v, ",")
+	}
+	if v, ok := lookupEnv("APP_DATA_PATH"); ok {
+		cfg.AppDataPath = v
+	}
+	if v, ok := lookupEnv("REPO_PATH"); ok {
+		cfg.RepoPath = v
+	}
+	if v, ok := lookupEnv("AUTH_USER"); ok {
+		cfg.AuthUser = v
+	}
+	if v, ok := lookupEnv("AUTH_PASS"); ok {
+		cfg.AuthPass = v
+	}
+	if v, ok := lookupEnvBool("VERBOSE"); ok {
+		cfg.Verbose = v
+	}
+	if v, ok := lookupEnvBool("DEBUG"); ok {
+		cfg.Debug = v
+	}
+}
+
+// lookupEnv reads envPrefix+name from the environment.
+func lookupEnv(name string) (string, bool) {
+	v, ok := os.LookupEnv(envPrefix + name)
+	if !ok || v == "" {
+		return "", false
+	}
+	return v, true
+}
+
+// lookupEnvBool reads envPrefix+name from the environment and parses it as a bool.
+func lookupEnvBool(name string) (bool, bool) {
+	v, ok := lookupEnv(name)
+	if !ok {
+		return false, false
+	}
+	parsed, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, false
+	}
+	return parsed, true
+}
@@ -0,0 +1,91 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v2"
+)
+
+// decodeMap parses data into a generic document, picking the codec from ext
+// (as returned by filepath.Ext, e.g. ".yaml"). YAML is the default for
+// unrecognized or missing extensions, matching gorilla's historical
+// config.yaml convention. A generic map (rather than decoding straight into
+// Configuration) is what lets loadFile merge in include: fragments and
+// resolve env://, file://, and dpapi:// secret references before the final
+// typed decode.
+func decodeMap(data []byte, ext string) (map[string]interface{}, error) {
+	var raw interface{}
+
+	switch strings.ToLower(ext) {
+	case ".json":
+		if err := json.Unmarshal(data, &raw); err != nil {
+			return nil, err
+		}
+	case ".toml":
+		if err := toml.Unmarshal(data, &raw); err != nil {
+			return nil, err
+		}
+	case ".yaml", ".yml", "":
+		if err := yaml.Unmarshal(data, &raw); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported configuration format %q", ext)
+	}
+
+	if raw == nil {
+		return map[string]interface{}{}, nil
+	}
+
+	return normalizeMap(raw), nil
+}
+
+// formatName returns a human readable name for ext, for use in error messages.
+func formatName(ext string) string {
+	switch strings.ToLower(ext) {
+	case ".json":
+		return "json"
+	case ".toml":
+		return "toml"
+	default:
+		return "yaml"
+	}
+}
+
+// normalizeMap recursively converts the map[interface{}]interface{} that
+// yaml.v2 produces into map[string]interface{}, so every codec's output can
+// be merged and walked uniformly.
+func normalizeMap(v interface{}) map[string]interface{} {
+	out := map[string]interface{}{}
+
+	switch m := v.(type) {
+	case map[string]interface{}:
+		for k, val := range m {
+			out[k] = normalizeValue(val)
+		}
+	case map[interface{}]interface{}:
+		for k, val := range m {
+			out[fmt.Sprintf("%v", k)] = normalizeValue(val)
+		}
+	}
+
+	return out
+}
+
+func normalizeValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}, map[interface{}]interface{}:
+		return normalizeMap(val)
+	case []interface{}:
+		items := make([]interface{}, len(val))
+		for i, item := range val {
+			items[i] = normalizeValue(item)
+		}
+		return items
+	default:
+		return val
+	}
+}
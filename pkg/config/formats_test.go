@@ -0,0 +1,36 @@
+package config
+
+import "testing"
+
+func TestDecodeMap_AllFormats(t *testing.T) {
+	cases := []struct {
+		name string
+		ext  string
+		data string
+	}{
+		{"yaml", ".yaml", "manifest: foo.yaml\nurl: https://repo.example.com\n"},
+		{"json", ".json", `{"manifest": "foo.yaml", "url": "https://repo.example.com"}`},
+		{"toml", ".toml", "manifest = \"foo.yaml\"\nurl = \"https://repo.example.com\"\n"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			doc, err := decodeMap([]byte(c.data), c.ext)
+			if err != nil {
+				t.Fatalf("decodeMap returned an error: %v", err)
+			}
+			if doc["manifest"] != "foo.yaml" {
+				t.Errorf("expected manifest to decode, got %v", doc["manifest"])
+			}
+			if doc["url"] != "https://repo.example.com" {
+				t.Errorf("expected url to decode, got %v", doc["url"])
+			}
+		})
+	}
+}
+
+func TestDecodeMap_UnsupportedExtension(t *testing.T) {
+	if _, err := decodeMap([]byte("irrelevant"), ".ini"); err == nil {
+		t.Error("expected an error for an unsupported extension")
+	}
+}
@@ -0,0 +1,84 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestResolveIncludes_MergesFragmentsLastWins(t *testing.T) {
+	dir := t.TempDir()
+
+	mustWrite(t, filepath.Join(dir, "config.d", "10-base.yaml"), "manifest: base.yaml\n")
+	mustWrite(t, filepath.Join(dir, "config.d", "20-override.yaml"), "manifest: override.yaml\ncatalogs:\n  - production\n")
+
+	doc := map[string]interface{}{
+		"url":     "https://repo.example.com",
+		"include": "config.d/*.yaml",
+	}
+
+	merged, err := resolveIncludes(doc, dir, signingPolicy{})
+	if err != nil {
+		t.Fatalf("resolveIncludes returned an error: %v", err)
+	}
+
+	if merged["manifest"] != "override.yaml" {
+		t.Errorf("expected the later-sorted fragment to win, got %v", merged["manifest"])
+	}
+	if _, ok := merged["include"]; ok {
+		t.Error("expected the include directive to be consumed, not merged into the result")
+	}
+	if merged["url"] != "https://repo.example.com" {
+		t.Errorf("expected unrelated base keys to survive, got %v", merged["url"])
+	}
+}
+
+func TestResolveIncludes_RejectsUnsignedFragmentWhenSigningRequired(t *testing.T) {
+	dir := t.TempDir()
+	mustWrite(t, filepath.Join(dir, "config.d", "10-frag.yaml"), "manifest: override.yaml\n")
+
+	doc := map[string]interface{}{
+		"include": "config.d/*.yaml",
+	}
+
+	pub, _ := generateTestKeyPair(t)
+	policy := signingPolicy{required: true, pubKey: pub}
+
+	if _, err := resolveIncludes(doc, dir, policy); err == nil {
+		t.Fatal("expected an unsigned fragment to be rejected when signing is required")
+	}
+}
+
+func TestMergeMaps_DeepMergesNestedMaps(t *testing.T) {
+	base := map[string]interface{}{
+		"reporting": map[string]interface{}{
+			"sinks": "file",
+			"kept":  "yes",
+		},
+	}
+	override := map[string]interface{}{
+		"reporting": map[string]interface{}{
+			"sinks": "http",
+		},
+	}
+
+	merged := mergeMaps(base, override)
+
+	reporting := merged["reporting"].(map[string]interface{})
+	if reporting["sinks"] != "http" {
+		t.Errorf("expected override to win for sinks, got %v", reporting["sinks"])
+	}
+	if reporting["kept"] != "yes" {
+		t.Errorf("expected untouched nested keys to survive the merge, got %v", reporting["kept"])
+	}
+}
+
+func mustWrite(t *testing.T, path string, contents string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("creating dir for %s: %v", path, err)
+	}
+	if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+		t.Fatalf("writing %s: %v", path, err)
+	}
+}
@@ -0,0 +1,29 @@
+package config
+
+// ReportingConfig describes where gorilla should send the structured report
+// document it produces at the end of each run.
+type ReportingConfig struct {
+	Sinks []SinkConfig `yaml:"sinks,omitempty" json:"sinks,omitempty" toml:"sinks,omitempty"`
+}
+
+// SinkConfig configures one reporting destination. Type selects which
+// fields apply: "file" (Path), "syslog" (Network, Address), "http" (URL,
+// Retries, BackoffSeconds), or "eventlog" (Source) - see pkg/reporting.
+type SinkConfig struct {
+	Type string `yaml:"type" json:"type" toml:"type"`
+
+	// file
+	Path string `yaml:"path,omitempty" json:"path,omitempty" toml:"path,omitempty"`
+
+	// syslog
+	Network string `yaml:"network,omitempty" json:"network,omitempty" toml:"network,omitempty"`
+	Address string `yaml:"address,omitempty" json:"address,omitempty" toml:"address,omitempty"`
+
+	// http
+	URL            string `yaml:"url,omitempty" json:"url,omitempty" toml:"url,omitempty"`
+	Retries        int    `yaml:"retries,omitempty" json:"retries,omitempty" toml:"retries,omitempty"`
+	BackoffSeconds int    `yaml:"backoff_seconds,omitempty" json:"backoff_seconds,omitempty" toml:"backoff_seconds,omitempty"`
+
+	// eventlog
+	Source string `yaml:"source,omitempty" json:"source,omitempty" toml:"source,omitempty"`
+}
@@ -0,0 +1,72 @@
+package config
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+// resolveSecrets walks doc in place, replacing any string value that looks
+// like a secret reference (env://NAME, file:///path, or dpapi://<blob> on
+// Windows) with the value it resolves to. This lets a config fragment
+// reference credentials without embedding them in plaintext.
+func resolveSecrets(doc map[string]interface{}) error {
+	for k, v := range doc {
+		resolved, err := resolveSecretValue(v)
+		if err != nil {
+			return fmt.Errorf("%s: %w", k, err)
+		}
+		doc[k] = resolved
+	}
+	return nil
+}
+
+func resolveSecretValue(v interface{}) (interface{}, error) {
+	switch val := v.(type) {
+	case string:
+		return resolveSecretString(val)
+	case map[string]interface{}:
+		if err := resolveSecrets(val); err != nil {
+			return nil, err
+		}
+		return val, nil
+	case []interface{}:
+		for i, item := range val {
+			resolved, err := resolveSecretValue(item)
+			if err != nil {
+				return nil, err
+			}
+			val[i] = resolved
+		}
+		return val, nil
+	default:
+		return v, nil
+	}
+}
+
+func resolveSecretString(s string) (string, error) {
+	switch {
+	case strings.HasPrefix(s, "env://"):
+		name := strings.TrimPrefix(s, "env://")
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			return "", fmt.Errorf("env secret %q is not set", name)
+		}
+		return value, nil
+
+	case strings.HasPrefix(s, "file://"):
+		path := strings.TrimPrefix(s, "file://")
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("unable to read file secret %q: %w", path, err)
+		}
+		return strings.TrimRight(string(data), "\r\n"), nil
+
+	case strings.HasPrefix(s, "dpapi://"):
+		return resolveDPAPISecret(strings.TrimPrefix(s, "dpapi://"))
+
+	default:
+		return s, nil
+	}
+}
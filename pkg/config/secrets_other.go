@@ -0,0 +1,11 @@
+//go:build !windows
+
+package config
+
+import "fmt"
+
+// resolveDPAPISecret is only meaningful on Windows, where DPAPI is
+// available; see secrets_windows.go.
+func resolveDPAPISecret(encoded string) (string, error) {
+	return "", fmt.Errorf("dpapi:// secrets are only supported on Windows")
+}
@@ -0,0 +1,68 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestResolveSecrets_EnvAndFileSchemes(t *testing.T) {
+	t.Setenv("TEST_SECRET", "s3cr3t")
+
+	secretFile := filepath.Join(t.TempDir(), "pass.txt")
+	if err := os.WriteFile(secretFile, []byte("filesecret\n"), 0644); err != nil {
+		t.Fatalf("writing secret file: %v", err)
+	}
+
+	doc := map[string]interface{}{
+		"auth_pass": "env://TEST_SECRET",
+		"auth_user": "file://" + secretFile,
+		"manifest":  "foo.yaml",
+	}
+
+	if err := resolveSecrets(doc); err != nil {
+		t.Fatalf("resolveSecrets returned an error: %v", err)
+	}
+
+	if doc["auth_pass"] != "s3cr3t" {
+		t.Errorf("expected env:// secret to resolve, got %v", doc["auth_pass"])
+	}
+	if doc["auth_user"] != "filesecret" {
+		t.Errorf("expected file:// secret to resolve with trailing newline trimmed, got %q", doc["auth_user"])
+	}
+	if doc["manifest"] != "foo.yaml" {
+		t.Errorf("expected plain values to pass through untouched, got %v", doc["manifest"])
+	}
+}
+
+func TestResolveSecrets_MissingEnvVarErrors(t *testing.T) {
+	doc := map[string]interface{}{"auth_pass": "env://DOES_NOT_EXIST_12345"}
+
+	if err := resolveSecrets(doc); err == nil {
+		t.Error("expected an error for an unset env secret")
+	}
+}
+
+func TestResolveSecrets_WalksNestedMapsAndLists(t *testing.T) {
+	t.Setenv("TEST_SECRET", "s3cr3t")
+
+	doc := map[string]interface{}{
+		"sources": []interface{}{
+			map[string]interface{}{
+				"auth": map[string]interface{}{
+					"pass": "env://TEST_SECRET",
+				},
+			},
+		},
+	}
+
+	if err := resolveSecrets(doc); err != nil {
+		t.Fatalf("resolveSecrets returned an error: %v", err)
+	}
+
+	sources := doc["sources"].([]interface{})
+	auth := sources[0].(map[string]interface{})["auth"].(map[string]interface{})
+	if auth["pass"] != "s3cr3t" {
+		t.Errorf("expected nested secret to resolve, got %v", auth["pass"])
+	}
+}
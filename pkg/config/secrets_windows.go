@@ -0,0 +1,39 @@
+//go:build windows
+
+package config
+
+import (
+	"encoding/base64"
+	"fmt"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// resolveDPAPISecret decrypts a base64-encoded blob that was protected with
+// Windows DPAPI (CryptProtectData), so a config fragment can carry a
+// credential that only decrypts on the machine/user it was encrypted for.
+// The decrypted bytes are returned as-is; hex-encoded blobs are not accepted.
+func resolveDPAPISecret(encoded string) (string, error) {
+	blob, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", fmt.Errorf("invalid dpapi secret encoding: %w", err)
+	}
+	if len(blob) == 0 {
+		return "", fmt.Errorf("dpapi secret is empty")
+	}
+
+	in := windows.DataBlob{
+		Size: uint32(len(blob)),
+		Data: &blob[0],
+	}
+	var out windows.DataBlob
+
+	err = windows.CryptUnprotectData(&in, nil, nil, 0, nil, 0, &out)
+	if err != nil {
+		return "", fmt.Errorf("unable to decrypt dpapi secret: %w", err)
+	}
+	defer windows.LocalFree(windows.Handle(unsafe.Pointer(out.Data)))
+
+	return string(unsafe.Slice(out.Data, out.Size)), nil
+}
@@ -0,0 +1,87 @@
+package config
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+)
+
+// signingPolicy carries the trust anchor extracted from the top-level
+// config file: whether signatures are required, and the pinned public key
+// to check them against. It is resolved once, from the top-level document
+// only, and then threaded into resolveIncludes so that config.d/ fragments
+// - plausibly less locked-down than the signed main file - can never loosen
+// or redirect it.
+type signingPolicy struct {
+	required bool
+	pubKey   ed25519.PublicKey
+}
+
+// resolveSigningPolicy reads require_signed_config/signed_config_public_key
+// from doc and, if signing is required, loads the pinned public key.
+func resolveSigningPolicy(doc map[string]interface{}) (signingPolicy, error) {
+	required, _ := doc["require_signed_config"].(bool)
+	if !required {
+		return signingPolicy{}, nil
+	}
+
+	keyPath, _ := doc["signed_config_public_key"].(string)
+	if keyPath == "" {
+		return signingPolicy{}, fmt.Errorf("require_signed_config is true but signed_config_public_key is not set")
+	}
+
+	pubKey, err := readEd25519PublicKey(keyPath)
+	if err != nil {
+		return signingPolicy{}, fmt.Errorf("unable to load signed config public key: %w", err)
+	}
+
+	return signingPolicy{required: true, pubKey: pubKey}, nil
+}
+
+// verify checks path against policy's pinned key using its detached
+// "<path>.sig" signature. It is a no-op when the policy doesn't require
+// signatures.
+func (policy signingPolicy) verify(path string, data []byte) error {
+	if !policy.required {
+		return nil
+	}
+
+	sigPath := path + ".sig"
+	sigData, err := ioutil.ReadFile(sigPath)
+	if err != nil {
+		return fmt.Errorf("require_signed_config is true but %s could not be read: %w", sigPath, err)
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(string(sigData))
+	if err != nil {
+		return fmt.Errorf("%s is not a valid base64 signature: %w", sigPath, err)
+	}
+
+	if !ed25519.Verify(policy.pubKey, data, signature) {
+		return fmt.Errorf("%s has an invalid signature for %s", sigPath, path)
+	}
+
+	return nil
+}
+
+// readEd25519PublicKey reads a PEM-encoded ed25519 public key from path. The
+// PEM block's bytes must be the raw 32-byte key; a PKIX (SubjectPublicKeyInfo)
+// encoded key is rejected.
+func readEd25519PublicKey(path string) (ed25519.PublicKey, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("%s does not contain PEM data", path)
+	}
+	if len(block.Bytes) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("%s does not contain a valid ed25519 public key", path)
+	}
+
+	return ed25519.PublicKey(block.Bytes), nil
+}
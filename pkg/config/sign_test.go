@@ -0,0 +1,101 @@
+package config
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// generateTestKeyPair returns a fresh ed25519 public key alongside the
+// private key for signing. Use writeTestPublicKey to put the public key on
+// disk.
+func generateTestKeyPair(t *testing.T) (ed25519.PublicKey, ed25519.PrivateKey) {
+	t.Helper()
+
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("generating test keypair: %v", err)
+	}
+	return pub, priv
+}
+
+func writeTestPublicKey(t *testing.T, pub ed25519.PublicKey) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "pubkey.pem")
+	block := &pem.Block{Type: "PUBLIC KEY", Bytes: pub}
+	if err := os.WriteFile(path, pem.EncodeToMemory(block), 0644); err != nil {
+		t.Fatalf("writing test public key: %v", err)
+	}
+	return path
+}
+
+func signTestFile(t *testing.T, priv ed25519.PrivateKey, path string, data []byte) {
+	t.Helper()
+	sig := ed25519.Sign(priv, data)
+	encoded := base64.StdEncoding.EncodeToString(sig)
+	if err := os.WriteFile(path+".sig", []byte(encoded), 0644); err != nil {
+		t.Fatalf("writing test signature: %v", err)
+	}
+}
+
+func TestSigningPolicy_VerifyAcceptsValidSignature(t *testing.T) {
+	pub, priv := generateTestKeyPair(t)
+	policy := signingPolicy{required: true, pubKey: pub}
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	data := []byte("manifest: foo.yaml\nurl: https://repo.example.com\n")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatalf("writing config: %v", err)
+	}
+	signTestFile(t, priv, path, data)
+
+	if err := policy.verify(path, data); err != nil {
+		t.Errorf("expected a validly signed file to verify, got %v", err)
+	}
+}
+
+func TestSigningPolicy_VerifyRejectsTamperedData(t *testing.T) {
+	pub, priv := generateTestKeyPair(t)
+	policy := signingPolicy{required: true, pubKey: pub}
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	data := []byte("manifest: foo.yaml\nurl: https://repo.example.com\n")
+	signTestFile(t, priv, path, data)
+
+	tampered := []byte("manifest: evil.yaml\nurl: https://attacker.example.com\n")
+	if err := policy.verify(path, tampered); err == nil {
+		t.Error("expected tampered data to fail signature verification")
+	}
+}
+
+func TestResolveSigningPolicy_RequiresPublicKeyPath(t *testing.T) {
+	doc := map[string]interface{}{"require_signed_config": true}
+
+	if _, err := resolveSigningPolicy(doc); err == nil {
+		t.Error("expected an error when require_signed_config is true but no key is configured")
+	}
+}
+
+func TestResolveSigningPolicy_LoadsPinnedKey(t *testing.T) {
+	pub, _ := generateTestKeyPair(t)
+	keyPath := writeTestPublicKey(t, pub)
+
+	doc := map[string]interface{}{
+		"require_signed_config":    true,
+		"signed_config_public_key": keyPath,
+	}
+
+	policy, err := resolveSigningPolicy(doc)
+	if err != nil {
+		t.Fatalf("resolveSigningPolicy returned an error: %v", err)
+	}
+	if !policy.required {
+		t.Error("expected policy.required to be true")
+	}
+	if !policy.pubKey.Equal(pub) {
+		t.Error("expected the loaded public key to match the one on disk")
+	}
+}
@@ -0,0 +1,88 @@
+package config
+
+import "sort"
+
+// Source describes one repository gorilla can resolve catalogs, manifests,
+// and packages from. Multiple sources let a deployment mix a public mirror
+// with a private, differently-authenticated repo, or provide a fallback
+// when the primary is unreachable.
+type Source struct {
+	Name        string `yaml:"name" json:"name" toml:"name"`
+	URL         string `yaml:"url" json:"url" toml:"url"`
+	URLPackages string `yaml:"url_packages,omitempty" json:"url_packages,omitempty" toml:"url_packages,omitempty"`
+	// Priority determines resolution order: sources with a higher Priority
+	// are tried first. Sources that tie are tried in the order they were
+	// declared.
+	Priority int  `yaml:"priority,omitempty" json:"priority,omitempty" toml:"priority,omitempty"`
+	Auth     Auth `yaml:"auth,omitempty" json:"auth,omitempty" toml:"auth,omitempty"`
+	TLS      TLS  `yaml:"tls,omitempty" json:"tls,omitempty" toml:"tls,omitempty"`
+}
+
+// Auth describes how to authenticate to a Source.
+type Auth struct {
+	// Type is one of "basic", "bearer", or "mtls". Empty means no auth.
+	Type  string `yaml:"type,omitempty" json:"type,omitempty" toml:"type,omitempty"`
+	User  string `yaml:"user,omitempty" json:"user,omitempty" toml:"user,omitempty"`
+	Pass  string `yaml:"pass,omitempty" json:"pass,omitempty" toml:"pass,omitempty"`
+	Token string `yaml:"token,omitempty" json:"token,omitempty" toml:"token,omitempty"`
+}
+
+// TLS describes the TLS settings to use when talking to a Source.
+type TLS struct {
+	ClientCert string `yaml:"client_cert,omitempty" json:"client_cert,omitempty" toml:"client_cert,omitempty"`
+	ClientKey  string `yaml:"client_key,omitempty" json:"client_key,omitempty" toml:"client_key,omitempty"`
+	ServerCert string `yaml:"server_cert,omitempty" json:"server_cert,omitempty" toml:"server_cert,omitempty"`
+}
+
+// normalizeSources ensures cfg.Sources is populated and sorted by priority
+// (highest first), which is the order pkg/source.Client walks them in,
+// falling back to the next source on failure. When the config file used the
+// flat, pre-multi-source keys (URL, AuthUser, TLSAuth, ...) instead of a
+// sources list, it synthesizes a single "default" source from them so the
+// rest of gorilla only ever has to deal with cfg.Sources.
+func normalizeSources(cfg *Configuration) {
+	if len(cfg.Sources) == 0 && cfg.URL != "" {
+		src := Source{
+			Name:        "default",
+			URL:         cfg.URL,
+			URLPackages: cfg.URLPackages,
+		}
+
+		if cfg.AuthUser != "" || cfg.AuthPass != "" {
+			src.Auth = Auth{Type: "basic", User: cfg.AuthUser, Pass: cfg.AuthPass}
+		}
+
+		if cfg.TLSAuth {
+			// Don't clobber a "basic" type already set above - mTLS is
+			// applied independently of Auth.Type (see source.httpClientFor),
+			// so a config combining AuthUser/AuthPass with TLSAuth still
+			// needs its basic-auth header sent alongside the client cert.
+			if src.Auth.Type == "" {
+				src.Auth.Type = "mtls"
+			}
+			src.TLS = TLS{
+				ClientCert: cfg.TLSClientCert,
+				ClientKey:  cfg.TLSClientKey,
+				ServerCert: cfg.TLSServerCert,
+			}
+		}
+
+		cfg.Sources = []Source{src}
+	}
+
+	sort.SliceStable(cfg.Sources, func(i, j int) bool {
+		return cfg.Sources[i].Priority > cfg.Sources[j].Priority
+	})
+
+	// Keep the flat fields in sync with the highest-priority source so older
+	// callers that still read cfg.URL directly keep working.
+	if len(cfg.Sources) > 0 {
+		primary := cfg.Sources[0]
+		cfg.URL = primary.URL
+		if primary.URLPackages != "" {
+			cfg.URLPackages = primary.URLPackages
+		} else {
+			cfg.URLPackages = primary.URL
+		}
+	}
+}
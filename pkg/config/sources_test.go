@@ -0,0 +1,72 @@
+package config
+
+import "testing"
+
+func TestNormalizeSources_SynthesizesLegacyFields(t *testing.T) {
+	cfg := &Configuration{
+		URL:      "https://repo.example.com",
+		AuthUser: "alice",
+		AuthPass: "hunter2",
+	}
+
+	normalizeSources(cfg)
+
+	if len(cfg.Sources) != 1 {
+		t.Fatalf("expected one synthesized source, got %d", len(cfg.Sources))
+	}
+	src := cfg.Sources[0]
+	if src.Name != "default" || src.URL != cfg.URL {
+		t.Errorf("unexpected synthesized source: %+v", src)
+	}
+	if src.Auth.Type != "basic" || src.Auth.User != "alice" || src.Auth.Pass != "hunter2" {
+		t.Errorf("expected basic auth carried over from AuthUser/AuthPass, got %+v", src.Auth)
+	}
+}
+
+func TestNormalizeSources_TLSAuthKeepsBasicCredentials(t *testing.T) {
+	cfg := &Configuration{
+		URL:           "https://repo.example.com",
+		AuthUser:      "alice",
+		AuthPass:      "hunter2",
+		TLSAuth:       true,
+		TLSClientCert: "client.pem",
+		TLSClientKey:  "client.key",
+		TLSServerCert: "server.pem",
+	}
+
+	normalizeSources(cfg)
+
+	src := cfg.Sources[0]
+	if src.Auth.Type != "basic" || src.Auth.User != "alice" || src.Auth.Pass != "hunter2" {
+		t.Errorf("expected TLSAuth to keep the basic-auth credentials alongside the client cert, got %+v", src.Auth)
+	}
+	if src.TLS.ClientCert != "client.pem" {
+		t.Errorf("expected the TLS client cert to still be wired, got %+v", src.TLS)
+	}
+}
+
+func TestNormalizeSources_SortsByPriorityDescending(t *testing.T) {
+	cfg := &Configuration{
+		Sources: []Source{
+			{Name: "mirror", URL: "https://mirror.example.com", Priority: 0},
+			{Name: "primary", URL: "https://primary.example.com", Priority: 10},
+			{Name: "fallback", URL: "https://fallback.example.com", Priority: -5},
+		},
+	}
+
+	normalizeSources(cfg)
+
+	got := []string{cfg.Sources[0].Name, cfg.Sources[1].Name, cfg.Sources[2].Name}
+	want := []string{"primary", "mirror", "fallback"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("expected sources sorted by descending priority %v, got %v", want, got)
+		}
+	}
+
+	// The flat fields stay in sync with the highest-priority source for
+	// older callers that still read cfg.URL directly.
+	if cfg.URL != "https://primary.example.com" {
+		t.Errorf("expected cfg.URL to mirror the highest-priority source, got %q", cfg.URL)
+	}
+}
@@ -0,0 +1,73 @@
+package config
+
+import (
+	"context"
+	"path/filepath"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// Watch re-reads and re-validates configPath whenever it changes on disk,
+// emitting a fresh Configuration snapshot on the returned channel. This lets
+// long-running agent modes pick up manifest/catalog changes without a
+// restart. The channel is closed when ctx is cancelled.
+func Watch(ctx context.Context, configPath string, verbose bool, debug bool) (<-chan Configuration, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+
+	// Watch the parent directory rather than the file itself so we still
+	// notice the common "editor writes a new file and renames it over the
+	// old one" save pattern.
+	dir := filepath.Dir(configPath)
+	if err := watcher.Add(dir); err != nil {
+		watcher.Close()
+		return nil, err
+	}
+
+	out := make(chan Configuration)
+
+	go func() {
+		defer watcher.Close()
+		defer close(out)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if filepath.Clean(event.Name) != filepath.Clean(configPath) {
+					continue
+				}
+				if event.Op&(fsnotify.Write|fsnotify.Create) == 0 {
+					continue
+				}
+
+				cfg, err := Load(configPath, verbose, debug)
+				if err != nil {
+					// Keep watching; a transient write (e.g. a partial
+					// save) shouldn't take the watcher down.
+					continue
+				}
+
+				select {
+				case out <- cfg:
+				case <-ctx.Done():
+					return
+				}
+
+			case _, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+			}
+		}
+	}()
+
+	return out, nil
+}
@@ -0,0 +1,30 @@
+// Package logging provides gorilla's leveled logger, built on log/slog,
+// used by pkg/config and pkg/agent to carry verbose/debug output.
+package logging
+
+import (
+	"io"
+	"log/slog"
+	"os"
+)
+
+// New returns a logger whose level is derived from the verbose/debug flags
+// gorilla already threads through pkg/config: debug implies verbose, and an
+// unset pair logs only warnings and above.
+func New(verbose bool, debug bool) *slog.Logger {
+	return NewWithWriter(os.Stdout, verbose, debug)
+}
+
+// NewWithWriter is New with an explicit destination, split out for testing.
+func NewWithWriter(w io.Writer, verbose bool, debug bool) *slog.Logger {
+	level := slog.LevelWarn
+	switch {
+	case debug:
+		level = slog.LevelDebug
+	case verbose:
+		level = slog.LevelInfo
+	}
+
+	handler := slog.NewTextHandler(w, &slog.HandlerOptions{Level: level})
+	return slog.New(handler)
+}
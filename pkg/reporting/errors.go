@@ -0,0 +1,28 @@
+package reporting
+
+import (
+	"fmt"
+	"strings"
+)
+
+// joinErrors combines errs into a single error, or returns nil if errs is
+// empty. A single error is returned unchanged; several are flattened into
+// one message, so the originals cannot be matched with errors.Is/As.
+func joinErrors(errs []error) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	if len(errs) == 1 {
+		return errs[0]
+	}
+
+	msgs := make([]string, len(errs))
+	for i, err := range errs {
+		msgs[i] = err.Error()
+	}
+	return fmt.Errorf("%d reporting sinks failed: %s", len(errs), strings.Join(msgs, "; "))
+}
+
+func unsupportedSinkType(t string) error {
+	return fmt.Errorf("unsupported reporting sink type %q", t)
+}
@@ -0,0 +1,15 @@
+//go:build !windows
+
+package reporting
+
+import (
+	"fmt"
+
+	"github.com/1dustindavis/gorilla/pkg/config"
+)
+
+// newEventlogSink is only meaningful on Windows, where the Event Log is
+// available; see eventlog_windows.go.
+func newEventlogSink(sc config.SinkConfig) (Sink, error) {
+	return nil, fmt.Errorf("the eventlog reporting sink is only supported on Windows")
+}
@@ -0,0 +1,42 @@
+//go:build windows
+
+package reporting
+
+import (
+	"context"
+	"encoding/json"
+
+	"golang.org/x/sys/windows/svc/eventlog"
+
+	"github.com/1dustindavis/gorilla/pkg/config"
+)
+
+// eventlogSink writes each Document, JSON-encoded, as an info event to the
+// Windows Event Log under sc.Source.
+type eventlogSink struct {
+	log *eventlog.Log
+}
+
+func newEventlogSink(sc config.SinkConfig) (Sink, error) {
+	source := sc.Source
+	if source == "" {
+		source = "Gorilla"
+	}
+
+	log, err := eventlog.Open(source)
+	if err != nil {
+		return nil, err
+	}
+
+	return &eventlogSink{log: log}, nil
+}
+
+func (e *eventlogSink) Emit(ctx context.Context, doc Document) error {
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+
+	const runReportEventID = 1
+	return e.log.Info(runReportEventID, string(data))
+}
@@ -0,0 +1,38 @@
+package reporting
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/1dustindavis/gorilla/pkg/config"
+)
+
+// fileSink appends one JSON line per Document to Path.
+type fileSink struct {
+	path string
+	mu   sync.Mutex
+}
+
+func newFileSink(sc config.SinkConfig) (Sink, error) {
+	if sc.Path == "" {
+		return nil, fmt.Errorf("file reporting sink requires a path")
+	}
+	return &fileSink{path: sc.Path}, nil
+}
+
+func (f *fileSink) Emit(ctx context.Context, doc Document) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	file, err := os.OpenFile(f.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("unable to open report file %q: %w", f.path, err)
+	}
+	defer file.Close()
+
+	encoder := json.NewEncoder(file)
+	return encoder.Encode(doc)
+}
@@ -0,0 +1,81 @@
+package reporting
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/1dustindavis/gorilla/pkg/config"
+)
+
+// httpSink POSTs each Document as JSON to a collector URL, retrying with a
+// linear backoff on failure.
+type httpSink struct {
+	url     string
+	retries int
+	backoff time.Duration
+	client  *http.Client
+}
+
+func newHTTPSink(sc config.SinkConfig) (Sink, error) {
+	if sc.URL == "" {
+		return nil, fmt.Errorf("http reporting sink requires a url")
+	}
+
+	retries := sc.Retries
+	if retries <= 0 {
+		retries = 3
+	}
+	backoffSeconds := sc.BackoffSeconds
+	if backoffSeconds <= 0 {
+		backoffSeconds = 5
+	}
+
+	return &httpSink{
+		url:     sc.URL,
+		retries: retries,
+		backoff: time.Duration(backoffSeconds) * time.Second,
+		client:  &http.Client{Timeout: 30 * time.Second},
+	}, nil
+}
+
+func (h *httpSink) Emit(ctx context.Context, doc Document) error {
+	body, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+
+	var lastErr error
+	for attempt := 0; attempt <= h.retries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-time.After(h.backoff * time.Duration(attempt)):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.url, bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := h.client.Do(req)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+			return nil
+		}
+		lastErr = fmt.Errorf("report collector %s returned %s", h.url, resp.Status)
+	}
+
+	return fmt.Errorf("giving up after %d attempts: %w", h.retries+1, lastErr)
+}
@@ -0,0 +1,75 @@
+// Package reporting builds the structured document gorilla emits once per
+// run and fans it out to the sinks named in Configuration.Reporting.
+package reporting
+
+import (
+	"context"
+	"time"
+
+	"github.com/1dustindavis/gorilla/pkg/config"
+)
+
+// Document is the structured record gorilla emits at the end of a run.
+type Document struct {
+	Manifest  string          `json:"manifest"`
+	Catalogs  []string        `json:"catalogs"`
+	StartedAt time.Time       `json:"started_at"`
+	Duration  time.Duration   `json:"duration"`
+	Installs  []InstallResult `json:"installs,omitempty"`
+	Errors    []string        `json:"errors,omitempty"`
+}
+
+// InstallResult records the outcome of attempting to install a single item.
+type InstallResult struct {
+	Name      string `json:"name"`
+	Attempted bool   `json:"attempted"`
+	Succeeded bool   `json:"succeeded"`
+	Error     string `json:"error,omitempty"`
+}
+
+// Sink emits a Document somewhere - a file, syslog, an HTTP collector, or
+// the Windows Event Log.
+type Sink interface {
+	Emit(ctx context.Context, doc Document) error
+}
+
+// Sinks builds a Sink for every entry in cfg.Reporting.Sinks.
+func Sinks(cfg config.Configuration) ([]Sink, error) {
+	sinks := make([]Sink, 0, len(cfg.Reporting.Sinks))
+	for _, sc := range cfg.Reporting.Sinks {
+		sink, err := newSink(sc)
+		if err != nil {
+			return nil, err
+		}
+		sinks = append(sinks, sink)
+	}
+	return sinks, nil
+}
+
+// Emit sends doc to every sink, returning a combined error for any that
+// failed rather than stopping at the first failure - one unreachable
+// collector shouldn't silently drop the rest of the run's reporting.
+func Emit(ctx context.Context, sinks []Sink, doc Document) error {
+	var errs []error
+	for _, sink := range sinks {
+		if err := sink.Emit(ctx, doc); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return joinErrors(errs)
+}
+
+func newSink(sc config.SinkConfig) (Sink, error) {
+	switch sc.Type {
+	case "file":
+		return newFileSink(sc)
+	case "syslog":
+		return newSyslogSink(sc)
+	case "http":
+		return newHTTPSink(sc)
+	case "eventlog":
+		return newEventlogSink(sc)
+	default:
+		return nil, unsupportedSinkType(sc.Type)
+	}
+}
@@ -0,0 +1,35 @@
+//go:build !windows
+
+package reporting
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/syslog"
+
+	"github.com/1dustindavis/gorilla/pkg/config"
+)
+
+// syslogSink writes each Document, JSON-encoded, as a single syslog message.
+type syslogSink struct {
+	writer *syslog.Writer
+}
+
+func newSyslogSink(sc config.SinkConfig) (Sink, error) {
+	// An empty network/address dials the local syslog daemon.
+	writer, err := syslog.Dial(sc.Network, sc.Address, syslog.LOG_INFO|syslog.LOG_DAEMON, "gorilla")
+	if err != nil {
+		return nil, fmt.Errorf("unable to connect to syslog: %w", err)
+	}
+
+	return &syslogSink{writer: writer}, nil
+}
+
+func (s *syslogSink) Emit(ctx context.Context, doc Document) error {
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	return s.writer.Info(string(data))
+}
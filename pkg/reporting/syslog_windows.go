@@ -0,0 +1,15 @@
+//go:build windows
+
+package reporting
+
+import (
+	"fmt"
+
+	"github.com/1dustindavis/gorilla/pkg/config"
+)
+
+// newSyslogSink is only meaningful on platforms with a syslog daemon; on
+// Windows use the "eventlog" sink instead (see eventlog_windows.go).
+func newSyslogSink(sc config.SinkConfig) (Sink, error) {
+	return nil, fmt.Errorf("the syslog reporting sink is not supported on Windows - use eventlog instead")
+}
@@ -0,0 +1,88 @@
+package source
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/1dustindavis/gorilla/pkg/config"
+)
+
+func TestClient_OpenFallsBackToSecondSourceWhenFirstErrors(t *testing.T) {
+	bad := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "down for maintenance", http.StatusServiceUnavailable)
+	}))
+	defer bad.Close()
+
+	good := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("manifest contents"))
+	}))
+	defer good.Close()
+
+	client := NewClient(config.Configuration{
+		Sources: []config.Source{
+			{Name: "primary", URL: bad.URL, Priority: 10},
+			{Name: "fallback", URL: good.URL, Priority: 0},
+		},
+	})
+
+	body, err := client.Open(context.Background(), "manifests/site_default.yaml")
+	if err != nil {
+		t.Fatalf("Open returned an error: %v", err)
+	}
+	defer body.Close()
+
+	got, err := ioutil.ReadAll(body)
+	if err != nil {
+		t.Fatalf("reading body: %v", err)
+	}
+	if string(got) != "manifest contents" {
+		t.Errorf("expected the fallback source's body, got %q", got)
+	}
+}
+
+func TestClient_OpenFailsWhenEverySourceErrors(t *testing.T) {
+	bad := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "nope", http.StatusNotFound)
+	}))
+	defer bad.Close()
+
+	client := NewClient(config.Configuration{
+		Sources: []config.Source{{Name: "primary", URL: bad.URL}},
+	})
+
+	if _, err := client.Open(context.Background(), "manifests/site_default.yaml"); err == nil {
+		t.Error("expected an error when every source fails")
+	}
+}
+
+func TestClient_OpenPackageFallsBackToURLWhenURLPackagesUnset(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/pkgs/Firefox.pkg" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte("package bytes"))
+	}))
+	defer server.Close()
+
+	client := NewClient(config.Configuration{
+		Sources: []config.Source{{Name: "default", URL: server.URL}},
+	})
+
+	body, err := client.OpenPackage(context.Background(), "pkgs/Firefox.pkg")
+	if err != nil {
+		t.Fatalf("OpenPackage returned an error: %v", err)
+	}
+	defer body.Close()
+
+	got, err := ioutil.ReadAll(body)
+	if err != nil {
+		t.Fatalf("reading body: %v", err)
+	}
+	if string(got) != "package bytes" {
+		t.Errorf("expected the served package bytes, got %q", got)
+	}
+}